@@ -0,0 +1,173 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"videotag-mvp/internal/library"
+)
+
+const basicAuthFailureDelay = 500 * time.Millisecond
+
+// adminRouter mounts the /admin subrouter, protected by HTTP Basic auth.
+func adminRouter(username, password string) http.Handler {
+	r := chi.NewRouter()
+	r.Use(basicAuth(username, password))
+
+	r.Get("/videos", handleListVideos)
+	r.Post("/videos/{id}/reanalyze", handleReanalyzeVideo)
+	r.Delete("/videos/{id}", handleDeleteVideo)
+	r.Post("/videos/{id}/rename", handleRenameVideo)
+
+	return r
+}
+
+// basicAuth checks incoming credentials against username/password using
+// constant-time comparisons, and sleeps a fixed delay before rejecting a bad
+// attempt to blunt brute-force timing and throughput.
+func basicAuth(username, password string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotUser, gotPass, ok := r.BasicAuth()
+			userMatch := subtle.ConstantTimeCompare([]byte(gotUser), []byte(username))
+			passMatch := subtle.ConstantTimeCompare([]byte(gotPass), []byte(password))
+
+			if !ok || userMatch&passMatch != 1 {
+				time.Sleep(basicAuthFailureDelay)
+				w.Header().Set("WWW-Authenticate", `Basic realm="admin"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// handleListVideos returns every video in the library.
+func handleListVideos(w http.ResponseWriter, r *http.Request) {
+	videos, err := videoLibrary.List()
+	if err != nil {
+		log.Printf("Error listing videos: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(videos)
+}
+
+// handleReanalyzeVideo re-enqueues a stored video for analysis.
+func handleReanalyzeVideo(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	video, err := videoLibrary.Get(id)
+	if err == library.ErrNotFound {
+		http.Error(w, "Video not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error loading video %s: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	job, err := jobQueue.Enqueue(video.Path, video.Filename)
+	if err != nil {
+		log.Printf("Error re-enqueueing video %s: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"job_id": job.ID})
+}
+
+// handleDeleteVideo removes a video and its file from the library.
+func handleDeleteVideo(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := videoLibrary.Delete(id)
+	if err == library.ErrNotFound {
+		http.Error(w, "Video not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error deleting video %s: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRenameVideo updates a video's display filename.
+func handleRenameVideo(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var body struct {
+		Filename string `json:"filename"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Filename == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := videoLibrary.Rename(id, body.Filename)
+	if err == library.ErrNotFound {
+		http.Error(w, "Video not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error renaming video %s: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// adminCredential reads an admin credential from the environment, falling
+// back to a default for local development.
+func adminCredential(envVar, fallback string) string {
+	if v := os.Getenv(envVar); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// adminPassword returns the ADMIN_PASSWORD environment variable, or, if
+// unset, generates a random one and logs it once. The admin API is
+// otherwise unauthenticated, so shipping a guessable default (e.g.
+// "admin") would be a real footgun for anyone who forgets to set it in
+// production.
+func adminPassword() (string, error) {
+	if v := os.Getenv("ADMIN_PASSWORD"); v != "" {
+		return v, nil
+	}
+
+	pass, err := randomPassword()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate admin password: %w", err)
+	}
+	log.Printf("ADMIN_PASSWORD not set; generated a random admin password for this run: %s", pass)
+	return pass, nil
+}
+
+func randomPassword() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -1,132 +1,505 @@
 package main
 
 import (
-	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
-	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"videotag-mvp/internal/jobs"
+	"videotag-mvp/internal/library"
+	"videotag-mvp/internal/processor"
+	"videotag-mvp/internal/uploads"
+	"videotag-mvp/internal/videovalidate"
 )
 
 const (
-	processorURL = "http://processor:8000/process"
-	maxFileSize  = 50 << 20 // 50MB
+	maxFileSize   = 50 << 20 // 50MB
+	uploadsDir    = "/tmp/uploads"
+	dataDir       = "/tmp/videotag-data"
+	uploadTTL     = 30 * time.Minute
+	janitorPeriod = 5 * time.Minute
+	jobWorkers    = 4
+)
+
+var (
+	uploadMgr       *uploads.Manager
+	jobQueue        *jobs.Queue
+	videoLibrary    *library.Library
+	videoPolicies   videovalidate.Policies
+	processorClient processor.Client
 )
 
 func main() {
+	mgr, err := uploads.NewManager(uploadsDir, uploadTTL)
+	if err != nil {
+		log.Fatalf("failed to initialize upload manager: %v", err)
+	}
+	uploadMgr = mgr
+
+	lib, err := library.New(dataDir)
+	if err != nil {
+		log.Fatalf("failed to initialize video library: %v", err)
+	}
+	videoLibrary = lib
+	defer videoLibrary.Close()
+
+	policies, err := videovalidate.LoadPolicies(os.Getenv("VIDEO_POLICY_CONFIG"))
+	if err != nil {
+		log.Fatalf("failed to load video policies: %v", err)
+	}
+	videoPolicies = policies
+
+	processorCfg, err := processor.LoadConfig(os.Getenv("PROCESSOR_CONFIG"))
+	if err != nil {
+		log.Fatalf("failed to load processor config: %v", err)
+	}
+	client, err := processor.Build(processorCfg)
+	if err != nil {
+		log.Fatalf("failed to initialize processor client: %v", err)
+	}
+	processorClient = client
+
+	queue, err := jobs.NewQueue(filepath.Join(uploadsDir, "jobs.db"), jobWorkers, processVideo)
+	if err != nil {
+		log.Fatalf("failed to initialize job queue: %v", err)
+	}
+	jobQueue = queue
+	defer jobQueue.Close()
+
+	janitorCtx, cancelJanitor := context.WithCancel(context.Background())
+	defer cancelJanitor()
+	go uploadMgr.RunJanitor(janitorCtx, janitorPeriod)
+
+	adminUser := adminCredential("ADMIN_USERNAME", "admin")
+	adminPass, err := adminPassword()
+	if err != nil {
+		log.Fatalf("failed to set up admin credentials: %v", err)
+	}
+
 	r := chi.NewRouter()
 
 	// Middleware
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
+	// Note: no request timeout here. /analyze and the resumable upload
+	// endpoints only enqueue work and return immediately; the job queue
+	// itself is what runs (and bounds the concurrency of) long analyses.
 
 	// Routes
 	r.Post("/analyze", handleAnalyze)
+	r.Post("/uploads", handleCreateUpload)
+	r.Patch("/uploads/{id}", handleAppendUpload)
+	r.Head("/uploads/{id}", handleUploadStatus)
+	r.Get("/jobs/{id}", handleJobStatus)
+	r.Get("/jobs/{id}/events", handleJobEvents)
 	r.Get("/health", handleHealth)
+	r.Get("/metrics", promhttp.Handler().ServeHTTP)
+	r.Mount("/admin", adminRouter(adminUser, adminPass))
 
 	log.Println("Starting VidiSnap API server on :8080")
 	log.Fatal(http.ListenAndServe(":8080", r))
 }
 
+// handleAnalyze streams the uploaded video straight to disk via
+// r.MultipartReader, rather than buffering the whole form in memory with
+// ParseMultipartForm, so uploads aren't bounded by available RAM. The actual
+// analysis runs asynchronously on the job queue; this handler only enqueues
+// it.
 func handleAnalyze(w http.ResponseWriter, r *http.Request) {
-	// Parse multipart form
-	if err := r.ParseMultipartForm(maxFileSize); err != nil {
+	mr, err := r.MultipartReader()
+	if err != nil {
 		http.Error(w, "Failed to parse form", http.StatusBadRequest)
 		return
 	}
 
-	// Get uploaded file
-	file, header, err := r.FormFile("file")
-	if err != nil {
+	var tmpPath, filename string
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			http.Error(w, "Failed to parse form", http.StatusBadRequest)
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		filename = part.FileName()
+		if !isValidVideoFile(filename) {
+			part.Close()
+			http.Error(w, "Invalid file type. Please upload a video file", http.StatusBadRequest)
+			return
+		}
+
+		id, err := randomID()
+		if err != nil {
+			part.Close()
+			log.Printf("Error generating upload id: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		tmpPath = filepath.Join(uploadsDir, id)
+		tmpFile, err := os.Create(tmpPath)
+		if err != nil {
+			part.Close()
+			log.Printf("Error creating temp file: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		written, copyErr := io.Copy(tmpFile, io.LimitReader(part, maxFileSize+1))
+		tmpFile.Close()
+		part.Close()
+		if copyErr != nil {
+			os.Remove(tmpPath)
+			log.Printf("Error saving file: %v", copyErr)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if written > maxFileSize {
+			os.Remove(tmpPath)
+			http.Error(w, "File too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		break
+	}
+
+	if tmpPath == "" {
 		http.Error(w, "No file uploaded", http.StatusBadRequest)
 		return
 	}
-	defer file.Close()
 
-	// Validate file type
-	if !isValidVideoFile(header.Filename) {
-		http.Error(w, "Invalid file type. Please upload a video file", http.StatusBadRequest)
+	if err := validateUpload(r.Context(), w, tmpPath, filename); err != nil {
+		os.Remove(tmpPath)
+		return
+	}
+
+	job, err := jobQueue.Enqueue(tmpPath, filepath.Base(filename))
+	if err != nil {
+		os.Remove(tmpPath)
+		log.Printf("Error enqueueing job: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	// Save file to /tmp
-	tmpPath := filepath.Join("/tmp", header.Filename)
-	tmpFile, err := os.Create(tmpPath)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"job_id": job.ID})
+}
+
+// handleCreateUpload reserves a resumable upload. The client declares the
+// total size via the tus-style Upload-Length header and, optionally, the
+// original filename via a tus Upload-Metadata "filename" entry, and gets
+// back an ID to PATCH bytes against.
+func handleCreateUpload(w http.ResponseWriter, r *http.Request) {
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length <= 0 {
+		http.Error(w, "Missing or invalid Upload-Length header", http.StatusBadRequest)
+		return
+	}
+	if length > maxFileSize {
+		http.Error(w, "File too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	filename := parseUploadMetadata(r.Header.Get("Upload-Metadata"))["filename"]
+
+	u, err := uploadMgr.Create(length, filename)
 	if err != nil {
-		log.Printf("Error creating temp file: %v", err)
+		log.Printf("Error creating upload: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
-	defer tmpFile.Close()
-	defer os.Remove(tmpPath) // Clean up after processing
 
-	if _, err := io.Copy(tmpFile, file); err != nil {
-		log.Printf("Error saving file: %v", err)
+	w.Header().Set("Location", "/uploads/"+u.ID)
+	w.Header().Set("Upload-Offset", "0")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"id": u.ID})
+}
+
+// handleAppendUpload appends a chunk of bytes at the offset given by the
+// Upload-Offset header (or a Content-Range byte offset), resuming a
+// previously interrupted upload. Once the upload is complete, it's handed
+// off to the job queue for asynchronous processing.
+func handleAppendUpload(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	offset, err := parseOffset(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	u, err := uploadMgr.Append(id, offset, r.Body)
+	switch err {
+	case nil:
+	case uploads.ErrNotFound:
+		http.Error(w, "Upload not found", http.StatusNotFound)
+		return
+	case uploads.ErrOffsetMismatch:
+		http.Error(w, "Offset mismatch", http.StatusConflict)
+		return
+	default:
+		log.Printf("Error appending upload %s: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(u.Offset, 10))
+
+	if !u.Complete() {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// Transfer ownership of the finished file from the upload manager to
+	// the job queue, which now owns cleaning it up.
+	u, err = uploadMgr.Take(id)
+	if err != nil {
+		log.Printf("Error taking completed upload %s: %v", id, err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	// Forward file to processor service
-	response, err := forwardToProcessor(tmpPath, header.Filename)
+	filename := u.Filename
+	if filename == "" {
+		filename = id
+	}
+
+	if err := validateUpload(r.Context(), w, u.Path, filename); err != nil {
+		os.Remove(u.Path)
+		return
+	}
+
+	job, err := jobQueue.Enqueue(u.Path, filename)
 	if err != nil {
-		log.Printf("Error forwarding to processor: %v", err)
-		http.Error(w, "Processing failed", http.StatusInternalServerError)
+		os.Remove(u.Path)
+		log.Printf("Error enqueueing upload %s: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	// Return processor response
 	w.Header().Set("Content-Type", "application/json")
-	w.Write(response)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"job_id": job.ID})
 }
 
-func forwardToProcessor(filePath, filename string) ([]byte, error) {
-	// Open the saved file
-	file, err := os.Open(filePath)
+// handleUploadStatus reports how many bytes have been received so far, so a
+// client that lost its connection knows where to resume from.
+func handleUploadStatus(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	u, err := uploadMgr.Status(id)
+	if err == uploads.ErrNotFound {
+		http.Error(w, "Upload not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
 	}
-	defer file.Close()
 
-	// Create multipart form
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
-	
-	part, err := writer.CreateFormFile("file", filename)
+	w.Header().Set("Upload-Offset", strconv.FormatInt(u.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(u.Length, 10))
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleJobStatus returns the current status, progress, and (if finished)
+// result of an analysis job.
+func handleJobStatus(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	job, err := jobQueue.Get(id)
+	if err == jobs.ErrNotFound {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job.Redacted())
+}
+
+// handleJobEvents streams job status as server-sent events until the job
+// finishes or the client disconnects.
+func handleJobEvents(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	job, err := jobQueue.Get(id)
+	if err == jobs.ErrNotFound {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	updates, unsubscribe, err := jobQueue.Subscribe(id)
+	if err != nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	writeEvent(w, flusher, job)
+	if isTerminal(job.Status) {
+		return
 	}
 
-	if _, err := io.Copy(part, file); err != nil {
-		return nil, fmt.Errorf("failed to copy file: %w", err)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case job, ok := <-updates:
+			if !ok {
+				return
+			}
+			writeEvent(w, flusher, job)
+			if isTerminal(job.Status) {
+				return
+			}
+		}
 	}
-	writer.Close()
+}
 
-	// Send request to processor
-	resp, err := http.Post(processorURL, writer.FormDataContentType(), &buf)
+func writeEvent(w http.ResponseWriter, flusher http.Flusher, job jobs.Job) {
+	data, err := json.Marshal(job.Redacted())
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request to processor: %w", err)
+		return
 	}
-	defer resp.Body.Close()
+	fmt.Fprintf(w, "data: %s\n\n", data)
+	flusher.Flush()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("processor returned status %d", resp.StatusCode)
+func isTerminal(s jobs.Status) bool {
+	return s == jobs.StatusSucceeded || s == jobs.StatusFailed
+}
+
+// processVideo is the job queue's ProcessFunc: it files the job's video into
+// the library (deduping by content hash), forwards it to the processor
+// service, and records the outcome against the library entry so it shows up
+// in the admin API.
+func processVideo(ctx context.Context, job *jobs.Job, progress jobs.ProgressFunc) (json.RawMessage, error) {
+	video, err := videoLibrary.Store(job.VideoPath, job.Filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to file video into library: %w", err)
 	}
 
-	// Read response
-	responseBody, err := io.ReadAll(resp.Body)
+	result, procErr := processorClient.Process(ctx, video.Path, video.Filename)
+	if procErr != nil {
+		videoLibrary.SetResult(video.ID, nil, library.StatusFailed, procErr.Error())
+		return nil, procErr
+	}
+
+	videoLibrary.SetResult(video.ID, result, library.StatusSucceeded, "")
+	return json.RawMessage(result), nil
+}
+
+// parseUploadMetadata decodes a tus Upload-Metadata header, a comma
+// separated list of "key base64(value)" pairs, into a plain map.
+func parseUploadMetadata(header string) map[string]string {
+	meta := make(map[string]string)
+	if header == "" {
+		return meta
+	}
+	for _, pair := range strings.Split(header, ",") {
+		fields := strings.Fields(strings.TrimSpace(pair))
+		if len(fields) != 2 {
+			continue
+		}
+		if decoded, err := base64.StdEncoding.DecodeString(fields[1]); err == nil {
+			meta[fields[0]] = string(decoded)
+		}
+	}
+	return meta
+}
+
+// parseOffset reads the upload offset from either the tus Upload-Offset
+// header or a standard Content-Range request header.
+func parseOffset(r *http.Request) (int64, error) {
+	if v := r.Header.Get("Upload-Offset"); v != "" {
+		offset, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid Upload-Offset header")
+		}
+		return offset, nil
+	}
+
+	if v := r.Header.Get("Content-Range"); v != "" {
+		var start, end, total int64
+		if _, err := fmt.Sscanf(v, "bytes %d-%d/%d", &start, &end, &total); err != nil {
+			return 0, fmt.Errorf("invalid Content-Range header")
+		}
+		return start, nil
+	}
+
+	return 0, fmt.Errorf("missing Upload-Offset or Content-Range header")
+}
+
+// validateUpload runs content-sniffing and ffprobe-based validation against
+// the file saved at tmpPath, writing a structured 415 response and
+// returning a non-nil error if it isn't a genuine, policy-compliant video.
+// On success it returns nil and writes nothing.
+func validateUpload(ctx context.Context, w http.ResponseWriter, tmpPath, declaredFilename string) error {
+	info, err := os.Stat(tmpPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		log.Printf("Error statting upload %s: %v", tmpPath, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return err
+	}
+
+	_, err = videovalidate.Validate(ctx, tmpPath, declaredFilename, info.Size(), videoPolicies)
+	if err == nil {
+		return nil
 	}
 
-	return responseBody, nil
+	var valErr *videovalidate.ValidationError
+	if !errors.As(err, &valErr) {
+		log.Printf("Error validating upload %s: %v", tmpPath, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnsupportedMediaType)
+	json.NewEncoder(w).Encode(map[string]string{
+		"error":         valErr.Reason,
+		"declared_ext":  valErr.DeclaredExt,
+		"detected_type": valErr.DetectedType,
+	})
+	return err
 }
 
 func isValidVideoFile(filename string) bool {
@@ -143,7 +516,17 @@ func isValidVideoFile(filename string) bool {
 	return validExtensions[ext]
 }
 
+// randomID returns a random hex-encoded identifier, matching the scheme
+// internal/jobs and internal/uploads already use for their own IDs.
+func randomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
 func handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Write([]byte(`{"status": "healthy", "service": "vidisnap-api"}`))
-} 
\ No newline at end of file
+}
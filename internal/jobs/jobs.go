@@ -0,0 +1,311 @@
+// Package jobs implements a bounded worker-pool job queue for video
+// analysis. Callers enqueue a job and get an ID back immediately; a fixed
+// number of workers pull from the queue and run the actual (potentially
+// long-running) processing, persisting progress so that a process restart
+// doesn't lose in-flight work.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Status is the lifecycle state of a Job.
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+var jobsBucket = []byte("jobs")
+
+// Job is a single unit of analysis work and its current progress.
+type Job struct {
+	ID        string          `json:"id"`
+	Filename  string          `json:"filename"`
+	Status    Status          `json:"status"`
+	Progress  int             `json:"progress"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+
+	// VideoPath is the on-disk location of the file to process. It's
+	// persisted so a restart can recover in-flight jobs, but callers that
+	// expose a Job to API clients should use Redacted to strip it first.
+	VideoPath string `json:"video_path"`
+}
+
+// Redacted returns a copy of the job with VideoPath cleared, for responses
+// that shouldn't leak server-side file paths to API clients.
+func (j Job) Redacted() Job {
+	j.VideoPath = ""
+	return j
+}
+
+// ProgressFunc lets a ProcessFunc report incremental progress (0-100) while
+// it runs.
+type ProgressFunc func(percent int)
+
+// ProcessFunc performs the actual analysis for a job and returns its result
+// payload.
+type ProcessFunc func(ctx context.Context, job *Job, progress ProgressFunc) (json.RawMessage, error)
+
+// ErrNotFound is returned when a job ID is unknown.
+var ErrNotFound = fmt.Errorf("job not found")
+
+// Queue is a persistent, bounded-concurrency job queue.
+type Queue struct {
+	db      *bolt.DB
+	process ProcessFunc
+	pending chan string
+
+	mu   sync.Mutex
+	jobs map[string]*Job
+	subs map[string][]chan Job
+}
+
+// NewQueue opens (or creates) the BoltDB file at dbPath and starts workers
+// goroutines to drain the queue, running process for each job. Any jobs left
+// "running" from a previous process (i.e. a crash) are re-queued.
+func NewQueue(dbPath string, workers int, process ProcessFunc) (*Queue, error) {
+	db, err := bolt.Open(dbPath, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open job store: %w", err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(jobsBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize job store: %w", err)
+	}
+
+	q := &Queue{
+		db:      db,
+		process: process,
+		pending: make(chan string, 256),
+		jobs:    make(map[string]*Job),
+		subs:    make(map[string][]chan Job),
+	}
+
+	recovered, err := q.loadAndRecover()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	for i := 0; i < workers; i++ {
+		go q.worker()
+	}
+
+	// Feed recovered jobs in after the workers are already draining pending,
+	// since there can be more of them than the channel's buffer and nothing
+	// would otherwise be reading while we're still inside NewQueue.
+	go func() {
+		for _, id := range recovered {
+			q.pending <- id
+		}
+	}()
+
+	return q, nil
+}
+
+// Close stops accepting new work and closes the underlying store. In-flight
+// jobs are left for the next NewQueue call to recover.
+func (q *Queue) Close() error {
+	return q.db.Close()
+}
+
+// Enqueue persists a new queued job for videoPath and schedules it for
+// processing, returning immediately.
+func (q *Queue) Enqueue(videoPath, filename string) (*Job, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate job id: %w", err)
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:        id,
+		Filename:  filename,
+		VideoPath: videoPath,
+		Status:    StatusQueued,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := q.save(job); err != nil {
+		return nil, err
+	}
+
+	q.mu.Lock()
+	q.jobs[id] = job
+	q.mu.Unlock()
+
+	q.pending <- id
+
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// Get returns the current state of a job.
+func (q *Queue) Get(id string) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return *job, nil
+}
+
+// Subscribe returns a channel that receives every update to job id, plus an
+// unsubscribe func the caller must invoke when done listening.
+func (q *Queue) Subscribe(id string) (<-chan Job, func(), error) {
+	q.mu.Lock()
+	if _, ok := q.jobs[id]; !ok {
+		q.mu.Unlock()
+		return nil, nil, ErrNotFound
+	}
+	ch := make(chan Job, 8)
+	q.subs[id] = append(q.subs[id], ch)
+	q.mu.Unlock()
+
+	unsubscribe := func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		subs := q.subs[id]
+		for i, c := range subs {
+			if c == ch {
+				q.subs[id] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}
+
+	return ch, unsubscribe, nil
+}
+
+func (q *Queue) worker() {
+	for id := range q.pending {
+		q.runJob(id)
+	}
+}
+
+func (q *Queue) runJob(id string) {
+	q.mu.Lock()
+	job, ok := q.jobs[id]
+	q.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	q.update(job, func(j *Job) {
+		j.Status = StatusRunning
+	})
+
+	ctx := context.Background()
+	result, err := q.process(ctx, job, func(percent int) {
+		q.update(job, func(j *Job) {
+			j.Progress = percent
+		})
+	})
+
+	q.update(job, func(j *Job) {
+		if err != nil {
+			j.Status = StatusFailed
+			j.Error = err.Error()
+			return
+		}
+		j.Status = StatusSucceeded
+		j.Progress = 100
+		j.Result = result
+	})
+}
+
+// update applies mutate to job under lock, persists it, and notifies
+// subscribers.
+func (q *Queue) update(job *Job, mutate func(*Job)) {
+	q.mu.Lock()
+	mutate(job)
+	job.UpdatedAt = time.Now()
+	snapshot := *job
+	subs := append([]chan Job(nil), q.subs[job.ID]...)
+	q.mu.Unlock()
+
+	if err := q.save(&snapshot); err != nil {
+		// The job continues to run in memory even if the persisted copy
+		// falls behind; the next update attempt may succeed.
+		fmt.Printf("jobs: failed to persist job %s: %v\n", job.ID, err)
+	}
+
+	for _, ch := range subs {
+		select {
+		case ch <- snapshot:
+		default:
+		}
+	}
+}
+
+func (q *Queue) save(job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+	return q.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Put([]byte(job.ID), data)
+	})
+}
+
+// loadAndRecover reads all persisted jobs into memory and returns the IDs of
+// any that were still queued or running when the process last stopped, so
+// the caller can re-queue them once workers are ready to drain q.pending.
+func (q *Queue) loadAndRecover() ([]string, error) {
+	var recovered []string
+	err := q.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(k, v []byte) error {
+			var job Job
+			if err := json.Unmarshal(v, &job); err != nil {
+				return fmt.Errorf("failed to decode job %s: %w", k, err)
+			}
+
+			requeue := job.Status == StatusRunning || job.Status == StatusQueued
+			if job.Status == StatusRunning {
+				job.Status = StatusQueued
+			}
+
+			jobCopy := job
+			q.jobs[job.ID] = &jobCopy
+			if requeue {
+				recovered = append(recovered, job.ID)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recovered, nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,180 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func noopProcess(ctx context.Context, job *Job, progress ProgressFunc) (json.RawMessage, error) {
+	return json.RawMessage(`{}`), nil
+}
+
+// waitAllTerminal polls until every job in ids has left StatusQueued and
+// StatusRunning, so the caller can close the queue without racing its
+// workers' final persistence writes.
+func waitAllTerminal(t *testing.T, q *Queue, ids []string) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		done := 0
+		for _, id := range ids {
+			job, err := q.Get(id)
+			if err != nil {
+				continue
+			}
+			if job.Status == StatusSucceeded || job.Status == StatusFailed {
+				done++
+			}
+		}
+		if done == len(ids) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("jobs did not reach a terminal status in time")
+}
+
+func TestRecovery_PreservesVideoPath(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "jobs.db")
+
+	block := make(chan struct{})
+	blocking := func(ctx context.Context, job *Job, progress ProgressFunc) (json.RawMessage, error) {
+		<-block
+		return json.RawMessage(`{}`), nil
+	}
+
+	q, err := NewQueue(dbPath, 1, blocking)
+	if err != nil {
+		t.Fatalf("NewQueue: %v", err)
+	}
+
+	job, err := q.Enqueue("/tmp/uploads/original-video.mp4", "original-video.mp4")
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	// Give the worker a moment to pick the job up and mark it running before
+	// we simulate a crash by closing the queue mid-run.
+	deadline := time.Now().Add(time.Second)
+	for {
+		got, err := q.Get(job.ID)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if got.Status == StatusRunning {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("job never reached StatusRunning")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Close the store without ever closing block, so the worker stays stuck
+	// mid-run (as it would after a real crash) rather than finishing cleanly.
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	q2, err := NewQueue(dbPath, 1, noopProcess)
+	if err != nil {
+		t.Fatalf("reopening queue: %v", err)
+	}
+	defer func() {
+		waitAllTerminal(t, q2, []string{job.ID})
+		q2.Close()
+	}()
+
+	recovered, err := q2.Get(job.ID)
+	if err != nil {
+		t.Fatalf("Get after recovery: %v", err)
+	}
+	if recovered.VideoPath != job.VideoPath {
+		t.Fatalf("VideoPath after recovery = %q, want %q", recovered.VideoPath, job.VideoPath)
+	}
+}
+
+func TestRedacted_ClearsVideoPath(t *testing.T) {
+	job := Job{ID: "abc", VideoPath: "/tmp/uploads/secret.mp4"}
+	redacted := job.Redacted()
+
+	if redacted.VideoPath != "" {
+		t.Fatalf("Redacted().VideoPath = %q, want empty", redacted.VideoPath)
+	}
+	if job.VideoPath == "" {
+		t.Fatalf("Redacted should not mutate the receiver's VideoPath")
+	}
+}
+
+func TestNewQueue_RecoversMoreJobsThanPendingBuffer(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "jobs.db")
+
+	// Seed more queued jobs directly in the store than the pending channel's
+	// buffer, so recovery would deadlock if NewQueue tried to feed them all
+	// in before any worker was running to drain the channel.
+	const numJobs = 300
+
+	db, err := bolt.Open(dbPath, 0o600, nil)
+	if err != nil {
+		t.Fatalf("opening db: %v", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(jobsBucket)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < numJobs; i++ {
+			job := Job{
+				ID:        fmt.Sprintf("job-%d", i),
+				Filename:  "video.mp4",
+				VideoPath: "/tmp/uploads/video.mp4",
+				Status:    StatusQueued,
+			}
+			data, err := json.Marshal(job)
+			if err != nil {
+				return err
+			}
+			if err := b.Put([]byte(job.ID), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("seeding jobs: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("closing seed db: %v", err)
+	}
+
+	done := make(chan *Queue, 1)
+	errc := make(chan error, 1)
+	go func() {
+		q, err := NewQueue(dbPath, 4, noopProcess)
+		if err != nil {
+			errc <- err
+			return
+		}
+		done <- q
+	}()
+
+	select {
+	case q := <-done:
+		ids := make([]string, numJobs)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("job-%d", i)
+		}
+		waitAllTerminal(t, q, ids)
+		q.Close()
+	case err := <-errc:
+		t.Fatalf("reopening queue with many recovered jobs: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("NewQueue deadlocked recovering jobs that exceed the pending channel buffer")
+	}
+}
@@ -0,0 +1,304 @@
+// Package library persists analyzed videos to a content-addressed data
+// directory so they can be listed, renamed, re-analyzed, or deleted through
+// the admin API, instead of living only as ephemeral temp files.
+package library
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Status mirrors the outcome of the most recent analysis run for a video.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+var videosBucket = []byte("videos")
+
+// ErrNotFound is returned when a video ID has no matching library entry.
+var ErrNotFound = fmt.Errorf("video not found")
+
+// Video is a single entry in the library: a stored file plus the result of
+// its most recent analysis.
+type Video struct {
+	ID   string `json:"id"` // sha256 of the file contents
+	Path string `json:"-"`
+	// Ext is the on-disk file extension, fixed at Store time. Path is
+	// derived from ID+Ext rather than Filename so that renaming the
+	// display name to a different extension can't point Path at a file
+	// that was never written.
+	Ext       string          `json:"ext"`
+	Filename  string          `json:"filename"`
+	Size      int64           `json:"size"`
+	Status    Status          `json:"status"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	AddedAt   time.Time       `json:"added_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Library stores video files under dataDir, named by content hash, with
+// metadata persisted in a BoltDB alongside them.
+type Library struct {
+	dir string
+	db  *bolt.DB
+	mu  sync.Mutex
+}
+
+// New opens (or creates) a library rooted at dataDir.
+func New(dataDir string) (*Library, error) {
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create library dir: %w", err)
+	}
+
+	db, err := bolt.Open(filepath.Join(dataDir, "library.db"), 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open library store: %w", err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(videosBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize library store: %w", err)
+	}
+
+	return &Library{dir: dataDir, db: db}, nil
+}
+
+// Close closes the underlying metadata store.
+func (l *Library) Close() error {
+	return l.db.Close()
+}
+
+// Store takes ownership of the file at srcPath, filing it into the library
+// under a name derived from its content hash, and records or updates its
+// metadata. If a video with the same content already exists, srcPath is
+// discarded (unless it is that entry's own stored file) and the existing
+// entry is returned. Callers no longer own srcPath once this returns
+// successfully; if it returns an error, srcPath may still be in place.
+func (l *Library) Store(srcPath, originalFilename string) (*Video, error) {
+	hash, size, err := hashFile(srcPath)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := sanitizeFilename(originalFilename)
+	if err != nil {
+		name = hash
+	}
+	ext := filepath.Ext(name)
+	targetPath := filepath.Join(l.dir, hash+ext)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if existing, err := l.get(hash); err == nil {
+		if srcPath != existing.Path {
+			os.Remove(srcPath)
+		}
+		return existing, nil
+	}
+
+	if srcPath != targetPath {
+		if err := moveFile(srcPath, targetPath); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	video := &Video{
+		ID:        hash,
+		Ext:       ext,
+		Filename:  name,
+		Path:      targetPath,
+		Size:      size,
+		Status:    StatusPending,
+		AddedAt:   now,
+		UpdatedAt: now,
+	}
+	if err := l.save(video); err != nil {
+		return nil, err
+	}
+	return video, nil
+}
+
+// SetResult records the outcome of an analysis run for an existing video.
+func (l *Library) SetResult(id string, result json.RawMessage, status Status, errMsg string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	video, err := l.get(id)
+	if err != nil {
+		return err
+	}
+	video.Status = status
+	video.Result = result
+	video.Error = errMsg
+	video.UpdatedAt = time.Now()
+	return l.save(video)
+}
+
+// Get returns a single video's metadata.
+func (l *Library) Get(id string) (*Video, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.get(id)
+}
+
+// List returns every video in the library.
+func (l *Library) List() ([]*Video, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var videos []*Video
+	err := l.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(videosBucket).ForEach(func(k, v []byte) error {
+			var video Video
+			if err := json.Unmarshal(v, &video); err != nil {
+				return fmt.Errorf("failed to decode video %s: %w", k, err)
+			}
+			video.Path = filepath.Join(l.dir, video.ID+video.Ext)
+			videos = append(videos, &video)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
+
+// Rename updates the display filename of a video. The name is sanitized the
+// same way an uploaded filename is. It only changes Filename, not the
+// on-disk file or its extension (tracked separately as Ext), since the
+// stored file is keyed by content hash and never moves.
+func (l *Library) Rename(id, filename string) error {
+	name, err := sanitizeFilename(filename)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	video, err := l.get(id)
+	if err != nil {
+		return err
+	}
+	video.Filename = name
+	video.UpdatedAt = time.Now()
+	return l.save(video)
+}
+
+// Delete removes a video's file and metadata from the library.
+func (l *Library) Delete(id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	video, err := l.get(id)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(video.Path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove video file: %w", err)
+	}
+	return l.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(videosBucket).Delete([]byte(id))
+	})
+}
+
+func (l *Library) get(id string) (*Video, error) {
+	var video Video
+	var found bool
+	err := l.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(videosBucket).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &video)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrNotFound
+	}
+	video.Path = filepath.Join(l.dir, video.ID+video.Ext)
+	return &video, nil
+}
+
+func (l *Library) save(video *Video) error {
+	data, err := json.Marshal(video)
+	if err != nil {
+		return fmt.Errorf("failed to marshal video: %w", err)
+	}
+	return l.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(videosBucket).Put([]byte(video.ID), data)
+	})
+}
+
+// sanitizeFilename strips any directory components from name and rejects
+// attempts to escape the library directory (e.g. via ".."), protecting
+// against path traversal through a client-supplied filename.
+func sanitizeFilename(name string) (string, error) {
+	base := filepath.Base(name)
+	if base == "." || base == string(filepath.Separator) || strings.Contains(base, "..") {
+		return "", fmt.Errorf("invalid filename %q", name)
+	}
+	return base, nil
+}
+
+func hashFile(path string) (hash string, size int64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open file for hashing: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to hash file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	// os.Rename fails across filesystems/devices; fall back to copy+remove.
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open source file: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create destination file: %w", err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return fmt.Errorf("failed to copy file into library: %w", err)
+	}
+	return os.Remove(src)
+}
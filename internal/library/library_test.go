@@ -0,0 +1,112 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTempFile(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing temp file: %v", err)
+	}
+	return path
+}
+
+func TestRename_CrossExtensionKeepsPathValid(t *testing.T) {
+	dataDir := t.TempDir()
+	lib, err := New(dataDir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer lib.Close()
+
+	srcDir := t.TempDir()
+	src := writeTempFile(t, srcDir, "clip.mp4", "video bytes")
+
+	video, err := lib.Store(src, "clip.mp4")
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if _, err := os.Stat(video.Path); err != nil {
+		t.Fatalf("stored file missing at %s: %v", video.Path, err)
+	}
+
+	if err := lib.Rename(video.ID, "clip.avi"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+
+	renamed, err := lib.Get(video.ID)
+	if err != nil {
+		t.Fatalf("Get after rename: %v", err)
+	}
+	if renamed.Filename != "clip.avi" {
+		t.Fatalf("Filename = %q, want clip.avi", renamed.Filename)
+	}
+	if _, err := os.Stat(renamed.Path); err != nil {
+		t.Fatalf("Path after rename to a different extension doesn't exist: %s: %v", renamed.Path, err)
+	}
+	if renamed.Path != video.Path {
+		t.Fatalf("Path changed after a display-name-only rename: %q -> %q", video.Path, renamed.Path)
+	}
+}
+
+func TestDelete_RemovesFileAfterRename(t *testing.T) {
+	dataDir := t.TempDir()
+	lib, err := New(dataDir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer lib.Close()
+
+	srcDir := t.TempDir()
+	src := writeTempFile(t, srcDir, "clip.mp4", "video bytes")
+
+	video, err := lib.Store(src, "clip.mp4")
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if err := lib.Rename(video.ID, "clip.avi"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+
+	if err := lib.Delete(video.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := os.Stat(video.Path); !os.IsNotExist(err) {
+		t.Fatalf("expected stored file to be removed, stat err = %v", err)
+	}
+	if _, err := lib.Get(video.ID); err != ErrNotFound {
+		t.Fatalf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStore_DedupesByContentHash(t *testing.T) {
+	dataDir := t.TempDir()
+	lib, err := New(dataDir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer lib.Close()
+
+	srcDir := t.TempDir()
+	src1 := writeTempFile(t, srcDir, "a.mp4", "same bytes")
+	src2 := writeTempFile(t, srcDir, "b.mp4", "same bytes")
+
+	first, err := lib.Store(src1, "a.mp4")
+	if err != nil {
+		t.Fatalf("Store first: %v", err)
+	}
+	second, err := lib.Store(src2, "b.mp4")
+	if err != nil {
+		t.Fatalf("Store second: %v", err)
+	}
+	if first.ID != second.ID {
+		t.Fatalf("expected identical content to dedupe to the same ID, got %q and %q", first.ID, second.ID)
+	}
+	if _, err := os.Stat(src2); !os.IsNotExist(err) {
+		t.Fatalf("expected duplicate source file to be discarded")
+	}
+}
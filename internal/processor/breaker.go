@@ -0,0 +1,128 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreakerClient wraps a Client with sony/gobreaker-style semantics:
+// it trips from closed to open after failureThreshold consecutive failures,
+// rejects calls outright while open, and after cooldown allows a single
+// half-open probe to decide whether to close again or re-open.
+type circuitBreakerClient struct {
+	next             Client
+	name             string
+	failureThreshold int
+	cooldown         time.Duration
+
+	mu            sync.Mutex
+	state         breakerState
+	failures      int
+	openedAt      time.Time
+	probeInFlight bool
+}
+
+// WithCircuitBreaker wraps client with a circuit breaker that opens after
+// failureThreshold consecutive failures and probes again after cooldown.
+func WithCircuitBreaker(client Client, name string, failureThreshold int, cooldown time.Duration) Client {
+	b := &circuitBreakerClient{
+		next:             client,
+		name:             name,
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+	}
+	b.setMetric()
+	return b
+}
+
+// ErrCircuitOpen is returned when a call is rejected because the breaker is
+// open.
+var ErrCircuitOpen = fmt.Errorf("processor: circuit breaker open")
+
+func (b *circuitBreakerClient) Process(ctx context.Context, filePath, filename string) ([]byte, error) {
+	if !b.allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	result, err := b.next.Process(ctx, filePath, filename)
+	b.record(err == nil)
+	return result, err
+}
+
+// allow reports whether a call should proceed, transitioning open->half-open
+// once the cooldown has elapsed and admitting exactly one probe at a time.
+func (b *circuitBreakerClient) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerClosed:
+		return true
+	case breakerHalfOpen:
+		if b.probeInFlight {
+			return false
+		}
+		b.probeInFlight = true
+		return true
+	default: // breakerOpen
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.probeInFlight = true
+		b.setMetricLocked()
+		return true
+	}
+}
+
+func (b *circuitBreakerClient) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probeInFlight = false
+
+	if success {
+		b.failures = 0
+		b.state = breakerClosed
+		b.setMetricLocked()
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.failureThreshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+	b.setMetricLocked()
+}
+
+func (b *circuitBreakerClient) setMetric() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.setMetricLocked()
+}
+
+func (b *circuitBreakerClient) setMetricLocked() {
+	breakerStateGauge.WithLabelValues(b.name).Set(float64(b.state))
+}
@@ -0,0 +1,121 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	failing := &stubClient{results: []stubResult{{err: errors.New("boom")}}}
+	client := WithCircuitBreaker(failing, "test", 3, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Process(context.Background(), "/tmp/f", "f.mp4"); err == nil {
+			t.Fatalf("call %d: expected the underlying failure, got nil", i)
+		}
+	}
+
+	// The threshold'th failure should have tripped the breaker; the next
+	// call must be rejected without reaching the underlying client.
+	_, err := client.Process(context.Background(), "/tmp/f", "f.mp4")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen", err)
+	}
+	if failing.calls != 3 {
+		t.Fatalf("calls = %d, want 3 (breaker should reject instead of calling through)", failing.calls)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenAfterCooldown(t *testing.T) {
+	failing := &stubClient{results: []stubResult{{err: errors.New("boom")}}}
+	const cooldown = 20 * time.Millisecond
+	client := WithCircuitBreaker(failing, "test", 1, cooldown)
+
+	if _, err := client.Process(context.Background(), "/tmp/f", "f.mp4"); err == nil {
+		t.Fatal("expected the underlying failure to trip the breaker")
+	}
+	if _, err := client.Process(context.Background(), "/tmp/f", "f.mp4"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen while cooling down", err)
+	}
+
+	time.Sleep(cooldown + 10*time.Millisecond)
+
+	// The breaker should now let exactly one probe through, and a
+	// successful probe should close it again.
+	breaker := client.(*circuitBreakerClient)
+	breaker.next = &stubClient{results: []stubResult{{body: []byte("ok")}}}
+
+	body, err := client.Process(context.Background(), "/tmp/f", "f.mp4")
+	if err != nil {
+		t.Fatalf("probe call: %v", err)
+	}
+	if string(body) != "ok" {
+		t.Fatalf("body = %q, want %q", body, "ok")
+	}
+
+	if _, err := client.Process(context.Background(), "/tmp/f", "f.mp4"); err != nil {
+		t.Fatalf("expected breaker to be closed after a successful probe, got %v", err)
+	}
+}
+
+func TestCircuitBreaker_SingleInFlightProbe(t *testing.T) {
+	block := make(chan struct{})
+	blocking := &blockingClient{block: block, body: []byte("ok")}
+
+	// Construct the breaker already open, with its cooldown elapsed, so
+	// both goroutines below race to become the half-open probe.
+	client := &circuitBreakerClient{
+		next:             blocking,
+		name:             "test",
+		failureThreshold: 1,
+		cooldown:         time.Millisecond,
+		state:            breakerOpen,
+		openedAt:         time.Now().Add(-time.Hour),
+	}
+
+	var wg sync.WaitGroup
+	results := make([]error, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := client.Process(context.Background(), "/tmp/f", "f.mp4")
+			results[i] = err
+		}(i)
+	}
+
+	// Give both goroutines a chance to reach the breaker before the
+	// in-flight probe completes.
+	time.Sleep(10 * time.Millisecond)
+	close(block)
+	wg.Wait()
+
+	var admitted, rejected int
+	for _, err := range results {
+		switch {
+		case err == nil:
+			admitted++
+		case errors.Is(err, ErrCircuitOpen):
+			rejected++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if admitted != 1 || rejected != 1 {
+		t.Fatalf("expected exactly one admitted probe and one rejection, got admitted=%d rejected=%d", admitted, rejected)
+	}
+}
+
+// blockingClient blocks Process until block is closed, then returns body.
+type blockingClient struct {
+	block chan struct{}
+	body  []byte
+}
+
+func (b *blockingClient) Process(ctx context.Context, filePath, filename string) ([]byte, error) {
+	<-b.block
+	return b.body, nil
+}
@@ -0,0 +1,34 @@
+// Package processor provides a pluggable client for the video processing
+// service, decoupling the API from any single transport or instance: it
+// can talk HTTP, gRPC, or an in-process implementation, retries transient
+// failures with backoff, trips a circuit breaker on a misbehaving backend,
+// and fans requests out across multiple replicas.
+package processor
+
+import (
+	"context"
+	"fmt"
+)
+
+// Client processes a single video file and returns the processor's raw
+// response body.
+type Client interface {
+	Process(ctx context.Context, filePath, filename string) ([]byte, error)
+}
+
+// RetryableError marks an error as safe to retry — connection failures and
+// 5xx responses, as opposed to e.g. a 4xx rejection that will never
+// succeed on retry.
+type RetryableError struct {
+	Err error
+}
+
+func (e *RetryableError) Error() string { return e.Err.Error() }
+func (e *RetryableError) Unwrap() error { return e.Err }
+
+func retryable(err error) error {
+	return &RetryableError{Err: err}
+}
+
+// ErrAllEndpointsDown is returned by a Pool that has no backing endpoints.
+var ErrAllEndpointsDown = fmt.Errorf("processor: all endpoints unavailable")
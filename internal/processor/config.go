@@ -0,0 +1,97 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Config selects and tunes the processor backend(s) the API talks to.
+type Config struct {
+	// Backend is "http", "grpc", or "local".
+	Backend   string   `json:"backend"`
+	Endpoints []string `json:"endpoints"`
+	// Strategy is "round_robin" or "least_loaded"; only meaningful with
+	// more than one endpoint.
+	Strategy                string `json:"strategy"`
+	MaxRetries              int    `json:"max_retries"`
+	RetryBaseDelayMS        int    `json:"retry_base_delay_ms"`
+	BreakerFailureThreshold int    `json:"breaker_failure_threshold"`
+	BreakerCooldownSeconds  int    `json:"breaker_cooldown_seconds"`
+}
+
+// DefaultConfig matches the repo's previous hardcoded single HTTP endpoint.
+func DefaultConfig() Config {
+	return Config{
+		Backend:                 "http",
+		Endpoints:               []string{"http://processor:8000/process"},
+		Strategy:                string(RoundRobin),
+		MaxRetries:              3,
+		RetryBaseDelayMS:        200,
+		BreakerFailureThreshold: 5,
+		BreakerCooldownSeconds:  30,
+	}
+}
+
+// LoadConfig reads processor configuration from a JSON file. An empty path
+// returns DefaultConfig.
+func LoadConfig(path string) (Config, error) {
+	if path == "" {
+		return DefaultConfig(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read processor config: %w", err)
+	}
+
+	cfg := DefaultConfig()
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to parse processor config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Build wires up a Client per cfg: one per configured endpoint (each
+// wrapped with metrics, retries, and a circuit breaker), fanned out via a
+// Pool using cfg.Strategy.
+func Build(cfg Config) (Client, error) {
+	if len(cfg.Endpoints) == 0 && cfg.Backend != "local" {
+		return nil, fmt.Errorf("processor config: at least one endpoint is required for backend %q", cfg.Backend)
+	}
+
+	endpoints := cfg.Endpoints
+	if cfg.Backend == "local" {
+		endpoints = []string{"local"}
+	}
+
+	clients := make([]Client, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		base, err := buildBackend(cfg.Backend, endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		wrapped := WithMetrics(base, endpoint)
+		// One initial attempt plus up to MaxRetries retries.
+		wrapped = WithRetry(wrapped, endpoint, cfg.MaxRetries+1, time.Duration(cfg.RetryBaseDelayMS)*time.Millisecond)
+		wrapped = WithCircuitBreaker(wrapped, endpoint, cfg.BreakerFailureThreshold, time.Duration(cfg.BreakerCooldownSeconds)*time.Second)
+		clients = append(clients, wrapped)
+	}
+
+	return NewPool(Strategy(cfg.Strategy), clients), nil
+}
+
+func buildBackend(backend, endpoint string) (Client, error) {
+	switch backend {
+	case "http":
+		return NewHTTPClient(endpoint), nil
+	case "grpc":
+		return NewGRPCClient(endpoint)
+	case "local":
+		return NewLocalClient(nil), nil
+	default:
+		return nil, fmt.Errorf("processor config: unknown backend %q", backend)
+	}
+}
@@ -0,0 +1,87 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+)
+
+// rawCodecName is registered with grpc's encoding package so GRPCClient can
+// send/receive the processor's raw JSON bytes without a generated protobuf
+// schema.
+const rawCodecName = "raw"
+
+func init() {
+	encoding.RegisterCodec(rawCodec{})
+}
+
+// rawCodec passes []byte payloads through unmodified instead of encoding
+// with protobuf.
+type rawCodec struct{}
+
+func (rawCodec) Name() string { return rawCodecName }
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("rawCodec: unsupported type %T", v)
+	}
+	return *b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("rawCodec: unsupported type %T", v)
+	}
+	*b = data
+	return nil
+}
+
+// GRPCClient forwards a video to the processor service's gRPC endpoint.
+type GRPCClient struct {
+	conn   *grpc.ClientConn
+	method string
+}
+
+// NewGRPCClient dials target (host:port) and returns a client that invokes
+// the processor's unary Process RPC.
+func NewGRPCClient(target string) (*GRPCClient, error) {
+	conn, err := grpc.Dial(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(rawCodecName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial processor gRPC endpoint: %w", err)
+	}
+	return &GRPCClient{conn: conn, method: "/processor.ProcessorService/Process"}, nil
+}
+
+// Close releases the underlying connection.
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+// Process implements Client. It streams the file into memory and sends it
+// as a single unary request; callers analyzing very large files should
+// prefer the HTTP backend's streaming upload instead.
+func (c *GRPCClient) Process(ctx context.Context, filePath, filename string) ([]byte, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var resp []byte
+	if err := c.conn.Invoke(ctx, c.method, &data, &resp); err != nil {
+		if st, ok := status.FromError(err); ok && st.Code().String() == "Unavailable" {
+			return nil, retryable(fmt.Errorf("processor gRPC endpoint unavailable: %w", err))
+		}
+		return nil, fmt.Errorf("processor gRPC call failed: %w", err)
+	}
+	return resp, nil
+}
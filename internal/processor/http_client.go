@@ -0,0 +1,77 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+)
+
+// HTTPClient forwards a video to a processor replica over HTTP, streaming
+// the file into the request body rather than buffering it.
+type HTTPClient struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// NewHTTPClient returns a client that posts to url using http.DefaultClient.
+func NewHTTPClient(url string) *HTTPClient {
+	return &HTTPClient{URL: url, HTTPClient: http.DefaultClient}
+}
+
+// Process implements Client.
+func (c *HTTPClient) Process(ctx context.Context, filePath, filename string) ([]byte, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+
+	go func() {
+		part, err := writer.CreateFormFile("file", filename)
+		if err != nil {
+			pw.CloseWithError(fmt.Errorf("failed to create form file: %w", err))
+			return
+		}
+		if _, err := io.Copy(part, file); err != nil {
+			pw.CloseWithError(fmt.Errorf("failed to copy file: %w", err))
+			return
+		}
+		if err := writer.Close(); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.Close()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, pr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build processor request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, retryable(fmt.Errorf("failed to send request to processor: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return nil, retryable(fmt.Errorf("processor returned status %d", resp.StatusCode))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("processor returned status %d", resp.StatusCode)
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return responseBody, nil
+}
@@ -0,0 +1,50 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AnalyzeFunc performs analysis directly in-process, with no network hop.
+// It's the hook a real embedded model or tagging pipeline would plug into.
+type AnalyzeFunc func(ctx context.Context, filePath, filename string) (map[string]interface{}, error)
+
+// LocalClient runs analysis in-process instead of forwarding to a separate
+// processor service — useful for local development and tests.
+type LocalClient struct {
+	Analyze AnalyzeFunc
+}
+
+// NewLocalClient returns a LocalClient. If analyze is nil, a stub that
+// reports no tags is used, matching the shape of a real processor response
+// without requiring a model to be wired up.
+func NewLocalClient(analyze AnalyzeFunc) *LocalClient {
+	if analyze == nil {
+		analyze = stubAnalyze
+	}
+	return &LocalClient{Analyze: analyze}
+}
+
+// Process implements Client.
+func (c *LocalClient) Process(ctx context.Context, filePath, filename string) ([]byte, error) {
+	result, err := c.Analyze(ctx, filePath, filename)
+	if err != nil {
+		return nil, fmt.Errorf("local analysis failed: %w", err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal local analysis result: %w", err)
+	}
+	return data, nil
+}
+
+func stubAnalyze(ctx context.Context, filePath, filename string) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"filename":    filename,
+		"tags":        []string{},
+		"analyzed_at": time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
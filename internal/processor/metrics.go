@@ -0,0 +1,50 @@
+package processor
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "processor_request_duration_seconds",
+		Help: "Latency of requests to a processor endpoint.",
+	}, []string{"endpoint", "outcome"})
+
+	retriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "processor_retries_total",
+		Help: "Number of retry attempts made against a processor endpoint.",
+	}, []string{"endpoint"})
+
+	breakerStateGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "processor_circuit_breaker_state",
+		Help: "Circuit breaker state per processor endpoint (0=closed, 1=open, 2=half_open).",
+	}, []string{"endpoint"})
+)
+
+// instrumentedClient wraps a Client with request latency metrics.
+type instrumentedClient struct {
+	next Client
+	name string
+}
+
+// WithMetrics wraps client so every call records its latency and outcome.
+func WithMetrics(client Client, name string) Client {
+	return &instrumentedClient{next: client, name: name}
+}
+
+func (c *instrumentedClient) Process(ctx context.Context, filePath, filename string) ([]byte, error) {
+	start := time.Now()
+	result, err := c.next.Process(ctx, filePath, filename)
+
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	requestDuration.WithLabelValues(c.name, outcome).Observe(time.Since(start).Seconds())
+
+	return result, err
+}
@@ -0,0 +1,95 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// Strategy selects which replica in a Pool handles the next request.
+type Strategy string
+
+const (
+	// RoundRobin cycles through replicas in order.
+	RoundRobin Strategy = "round_robin"
+	// LeastLoaded sends each request to the replica with the fewest
+	// in-flight requests.
+	LeastLoaded Strategy = "least_loaded"
+)
+
+// replica is one endpoint in a Pool, tracking how many requests it's
+// currently handling for LeastLoaded selection.
+type replica struct {
+	client   Client
+	inFlight int64
+}
+
+// Pool fans requests out across multiple processor replicas, falling back to
+// one other replica when the chosen one's circuit breaker is open.
+type Pool struct {
+	strategy Strategy
+	replicas []*replica
+	next     uint64 // round-robin cursor
+}
+
+// NewPool returns a Pool that distributes requests across clients using
+// strategy.
+func NewPool(strategy Strategy, clients []Client) *Pool {
+	replicas := make([]*replica, len(clients))
+	for i, c := range clients {
+		replicas[i] = &replica{client: c}
+	}
+	return &Pool{strategy: strategy, replicas: replicas}
+}
+
+// Process implements Client, selecting one replica per the pool's strategy.
+// If that replica's breaker is open and the pool has more than one replica,
+// it picks a second replica the same way and, if that is a different one,
+// returns its result instead; no further replicas are tried, so the caller
+// gets ErrCircuitOpen when both are tripped. A pool with no replicas returns
+// ErrAllEndpointsDown.
+func (p *Pool) Process(ctx context.Context, filePath, filename string) ([]byte, error) {
+	if len(p.replicas) == 0 {
+		return nil, ErrAllEndpointsDown
+	}
+
+	r := p.choose()
+	atomic.AddInt64(&r.inFlight, 1)
+	defer atomic.AddInt64(&r.inFlight, -1)
+
+	result, err := r.client.Process(ctx, filePath, filename)
+	if errors.Is(err, ErrCircuitOpen) && len(p.replicas) > 1 {
+		// Give one other replica a chance before giving up, so a single
+		// tripped breaker doesn't fail every request while others are
+		// healthy.
+		alt := p.choose()
+		if alt != r {
+			atomic.AddInt64(&alt.inFlight, 1)
+			defer atomic.AddInt64(&alt.inFlight, -1)
+			return alt.client.Process(ctx, filePath, filename)
+		}
+	}
+	return result, err
+}
+
+func (p *Pool) choose() *replica {
+	if p.strategy == LeastLoaded {
+		return p.leastLoaded()
+	}
+	return p.roundRobin()
+}
+
+func (p *Pool) roundRobin() *replica {
+	i := atomic.AddUint64(&p.next, 1) - 1
+	return p.replicas[i%uint64(len(p.replicas))]
+}
+
+func (p *Pool) leastLoaded() *replica {
+	best := p.replicas[0]
+	for _, r := range p.replicas[1:] {
+		if atomic.LoadInt64(&r.inFlight) < atomic.LoadInt64(&best.inFlight) {
+			best = r
+		}
+	}
+	return best
+}
@@ -0,0 +1,102 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// openClient always returns ErrCircuitOpen, simulating a replica whose
+// breaker has tripped.
+type openClient struct{ calls int }
+
+func (c *openClient) Process(ctx context.Context, filePath, filename string) ([]byte, error) {
+	c.calls++
+	return nil, ErrCircuitOpen
+}
+
+func TestPool_FallsBackToAltReplicaOnCircuitOpen(t *testing.T) {
+	tripped := &openClient{}
+	healthy := &stubClient{results: []stubResult{{body: []byte("ok")}}}
+
+	pool := NewPool(RoundRobin, []Client{tripped, healthy})
+	// Force the round-robin cursor to land on the tripped replica first.
+	pool.next = 0
+
+	body, err := pool.Process(context.Background(), "/tmp/f", "f.mp4")
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if string(body) != "ok" {
+		t.Fatalf("body = %q, want %q", body, "ok")
+	}
+	if tripped.calls != 1 {
+		t.Fatalf("tripped replica calls = %d, want 1", tripped.calls)
+	}
+	if healthy.calls != 1 {
+		t.Fatalf("healthy replica calls = %d, want 1 (alt-replica fallback)", healthy.calls)
+	}
+}
+
+func TestPool_SingleTrippedReplicaReturnsCircuitOpen(t *testing.T) {
+	tripped := &openClient{}
+	pool := NewPool(RoundRobin, []Client{tripped})
+
+	_, err := pool.Process(context.Background(), "/tmp/f", "f.mp4")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen (no other replica to fall back to)", err)
+	}
+}
+
+func TestPool_NoReplicasReturnsAllEndpointsDown(t *testing.T) {
+	pool := NewPool(RoundRobin, nil)
+
+	_, err := pool.Process(context.Background(), "/tmp/f", "f.mp4")
+	if !errors.Is(err, ErrAllEndpointsDown) {
+		t.Fatalf("err = %v, want ErrAllEndpointsDown", err)
+	}
+}
+
+func TestPool_LeastLoadedPicksFewestInFlight(t *testing.T) {
+	block := make(chan struct{})
+	busy := &blockingClient{block: block}
+	idle := &stubClient{results: []stubResult{{body: []byte("idle")}}}
+
+	pool := NewPool(LeastLoaded, []Client{busy, idle})
+
+	done := make(chan struct{})
+	go func() {
+		pool.Process(context.Background(), "/tmp/busy", "busy.mp4")
+		close(done)
+	}()
+
+	// Give the busy replica's in-flight counter a moment to increment
+	// before the next Process call has to choose between replicas.
+	<-waitInFlight(pool, 0)
+
+	body, err := pool.Process(context.Background(), "/tmp/f", "f.mp4")
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if string(body) != "idle" {
+		t.Fatalf("body = %q, want %q (least-loaded replica)", body, "idle")
+	}
+
+	close(block)
+	<-done
+}
+
+// waitInFlight returns a channel that closes once replicas[idx] shows at
+// least one in-flight request.
+func waitInFlight(p *Pool, idx int) <-chan struct{} {
+	ch := make(chan struct{})
+	go func() {
+		for atomic.LoadInt64(&p.replicas[idx].inFlight) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		close(ch)
+	}()
+	return ch
+}
@@ -0,0 +1,60 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// retryingClient wraps a Client, retrying calls that fail with a
+// RetryableError using exponential backoff with jitter.
+type retryingClient struct {
+	next      Client
+	name      string
+	maxTries  int
+	baseDelay time.Duration
+}
+
+// WithRetry wraps client so that up to maxTries attempts are made for any
+// call that fails with a retryable (connection or 5xx) error, waiting
+// baseDelay*2^(n-1) (plus up to half that again as jitter) before the nth
+// retry. name identifies this endpoint in retry metrics.
+func WithRetry(client Client, name string, maxTries int, baseDelay time.Duration) Client {
+	return &retryingClient{next: client, name: name, maxTries: maxTries, baseDelay: baseDelay}
+}
+
+func (c *retryingClient) Process(ctx context.Context, filePath, filename string) ([]byte, error) {
+	var lastErr error
+
+	for attempt := 0; attempt < c.maxTries; attempt++ {
+		if attempt > 0 {
+			retriesTotal.WithLabelValues(c.name).Inc()
+			delay := backoffDelay(c.baseDelay, attempt)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		result, err := c.next.Process(ctx, filePath, filename)
+		if err == nil {
+			return result, nil
+		}
+
+		lastErr = err
+		var retryErr *RetryableError
+		if !errors.As(err, &retryErr) {
+			return nil, err
+		}
+	}
+
+	return nil, lastErr
+}
+
+func backoffDelay(base time.Duration, attempt int) time.Duration {
+	delay := base << uint(attempt-1)
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay + jitter
+}
@@ -0,0 +1,113 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// stubClient returns the next result from results each call, looping the
+// last entry if called more times than results has entries.
+type stubClient struct {
+	results []stubResult
+	calls   int
+}
+
+type stubResult struct {
+	body []byte
+	err  error
+}
+
+func (s *stubClient) Process(ctx context.Context, filePath, filename string) ([]byte, error) {
+	i := s.calls
+	if i >= len(s.results) {
+		i = len(s.results) - 1
+	}
+	s.calls++
+	return s.results[i].body, s.results[i].err
+}
+
+func TestRetryingClient_SucceedsWithoutRetry(t *testing.T) {
+	stub := &stubClient{results: []stubResult{{body: []byte("ok")}}}
+	client := WithRetry(stub, "test", 3, time.Millisecond)
+
+	body, err := client.Process(context.Background(), "/tmp/f", "f.mp4")
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if string(body) != "ok" {
+		t.Fatalf("body = %q, want %q", body, "ok")
+	}
+	if stub.calls != 1 {
+		t.Fatalf("calls = %d, want 1 (no retry on success)", stub.calls)
+	}
+}
+
+func TestRetryingClient_RetriesRetryableErrors(t *testing.T) {
+	stub := &stubClient{results: []stubResult{
+		{err: retryable(errors.New("connection reset"))},
+		{err: retryable(errors.New("connection reset"))},
+		{body: []byte("ok")},
+	}}
+	client := WithRetry(stub, "test", 5, time.Millisecond)
+
+	body, err := client.Process(context.Background(), "/tmp/f", "f.mp4")
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if string(body) != "ok" {
+		t.Fatalf("body = %q, want %q", body, "ok")
+	}
+	if stub.calls != 3 {
+		t.Fatalf("calls = %d, want 3", stub.calls)
+	}
+}
+
+func TestRetryingClient_NonRetryableErrorFailsFast(t *testing.T) {
+	wantErr := errors.New("400 bad request")
+	stub := &stubClient{results: []stubResult{
+		{err: wantErr},
+		{body: []byte("ok")},
+	}}
+	client := WithRetry(stub, "test", 5, time.Millisecond)
+
+	_, err := client.Process(context.Background(), "/tmp/f", "f.mp4")
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if stub.calls != 1 {
+		t.Fatalf("calls = %d, want 1 (no retry on a non-retryable error)", stub.calls)
+	}
+}
+
+func TestRetryingClient_ExhaustsMaxTries(t *testing.T) {
+	lastErr := retryable(fmt.Errorf("still down"))
+	stub := &stubClient{results: []stubResult{{err: lastErr}}}
+	client := WithRetry(stub, "test", 3, time.Millisecond)
+
+	_, err := client.Process(context.Background(), "/tmp/f", "f.mp4")
+	if !errors.Is(err, lastErr) {
+		t.Fatalf("err = %v, want %v", err, lastErr)
+	}
+	if stub.calls != 3 {
+		t.Fatalf("calls = %d, want 3 (maxTries)", stub.calls)
+	}
+}
+
+func TestRetryingClient_ContextCancelledDuringBackoff(t *testing.T) {
+	stub := &stubClient{results: []stubResult{
+		{err: retryable(errors.New("down"))},
+		{body: []byte("ok")},
+	}}
+	client := WithRetry(stub, "test", 5, 50*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.Process(ctx, "/tmp/f", "f.mp4")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
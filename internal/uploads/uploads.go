@@ -0,0 +1,245 @@
+// Package uploads implements a tus-style resumable upload subsystem: clients
+// reserve an upload with a declared length, then PATCH bytes at an offset
+// until the file is complete. Partial uploads are backed by a sparse file on
+// disk so a dropped connection can resume without re-sending already-written
+// bytes.
+package uploads
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when an upload ID has no matching in-progress or
+// completed upload.
+var ErrNotFound = fmt.Errorf("upload not found")
+
+// ErrOffsetMismatch is returned when a PATCH arrives at an offset other than
+// the upload's current offset, which means the client and server have
+// diverged and the client must re-sync via HEAD.
+var ErrOffsetMismatch = fmt.Errorf("offset mismatch")
+
+// Upload tracks the progress of a single resumable upload.
+type Upload struct {
+	ID           string
+	Filename     string
+	Length       int64
+	Offset       int64
+	Path         string
+	LastActivity time.Time
+
+	// writeMu serializes Append calls for this upload, so two PATCHes
+	// racing in at the same offset (as resumable-upload clients do after a
+	// timeout) can't both pass the offset check and both write.
+	writeMu *sync.Mutex
+}
+
+// Complete reports whether every declared byte has been written.
+func (u *Upload) Complete() bool {
+	return u.Offset >= u.Length
+}
+
+// Manager stores partial uploads under a directory, keyed by ID, and expires
+// ones that go stale.
+type Manager struct {
+	dir string
+	ttl time.Duration
+
+	mu      sync.Mutex
+	uploads map[string]*Upload
+}
+
+// NewManager creates a Manager rooted at dir, creating it if necessary.
+// Uploads that receive no bytes for longer than ttl are eligible for janitor
+// cleanup.
+func NewManager(dir string, ttl time.Duration) (*Manager, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create upload dir: %w", err)
+	}
+	return &Manager{
+		dir:     dir,
+		ttl:     ttl,
+		uploads: make(map[string]*Upload),
+	}, nil
+}
+
+// Create reserves a new upload of the given declared length and filename
+// and allocates a sparse file for it.
+func (m *Manager) Create(length int64, filename string) (*Upload, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate upload id: %w", err)
+	}
+
+	path := filepath.Join(m.dir, id)
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload file: %w", err)
+	}
+	if err := f.Truncate(length); err != nil {
+		f.Close()
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to allocate upload file: %w", err)
+	}
+	f.Close()
+
+	u := &Upload{
+		ID:           id,
+		Filename:     filename,
+		Length:       length,
+		Path:         path,
+		LastActivity: time.Now(),
+		writeMu:      &sync.Mutex{},
+	}
+
+	m.mu.Lock()
+	m.uploads[id] = u
+	m.mu.Unlock()
+
+	return u, nil
+}
+
+// Status returns a snapshot of the named upload.
+func (m *Manager) Status(id string) (Upload, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, ok := m.uploads[id]
+	if !ok {
+		return Upload{}, ErrNotFound
+	}
+	return *u, nil
+}
+
+// Append writes r at offset into the upload's backing file and advances its
+// offset. It fails with ErrOffsetMismatch if offset doesn't match the
+// upload's current progress, since that means bytes were lost or replayed.
+// The check, write, and offset update are serialized per-upload via
+// writeMu, so two concurrent PATCHes at the same offset can't both pass the
+// check and corrupt the file.
+func (m *Manager) Append(id string, offset int64, r io.Reader) (Upload, error) {
+	m.mu.Lock()
+	u, ok := m.uploads[id]
+	m.mu.Unlock()
+	if !ok {
+		return Upload{}, ErrNotFound
+	}
+
+	u.writeMu.Lock()
+	defer u.writeMu.Unlock()
+
+	m.mu.Lock()
+	curOffset := u.Offset
+	m.mu.Unlock()
+	if offset != curOffset {
+		return Upload{}, ErrOffsetMismatch
+	}
+
+	f, err := os.OpenFile(u.Path, os.O_WRONLY, 0o644)
+	if err != nil {
+		return Upload{}, fmt.Errorf("failed to open upload file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return Upload{}, fmt.Errorf("failed to seek upload file: %w", err)
+	}
+
+	n, err := io.Copy(f, io.LimitReader(r, u.Length-offset))
+	if err != nil {
+		return Upload{}, fmt.Errorf("failed to write upload chunk: %w", err)
+	}
+
+	m.mu.Lock()
+	u.Offset += n
+	u.LastActivity = time.Now()
+	snapshot := *u
+	m.mu.Unlock()
+
+	return snapshot, nil
+}
+
+// Remove deletes an upload's backing file and forgets it.
+func (m *Manager) Remove(id string) error {
+	m.mu.Lock()
+	u, ok := m.uploads[id]
+	delete(m.uploads, id)
+	m.mu.Unlock()
+
+	if !ok {
+		return ErrNotFound
+	}
+	if err := os.Remove(u.Path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove upload file: %w", err)
+	}
+	return nil
+}
+
+// Take hands ownership of a completed upload's backing file to the caller
+// and stops tracking it, without deleting the file. It's used once an
+// upload finishes and its file is handed off for processing.
+func (m *Manager) Take(id string) (Upload, error) {
+	m.mu.Lock()
+	u, ok := m.uploads[id]
+	if ok {
+		delete(m.uploads, id)
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		return Upload{}, ErrNotFound
+	}
+	return *u, nil
+}
+
+// RunJanitor periodically removes uploads that have gone silent for longer
+// than the manager's ttl. It blocks until ctx is cancelled, so callers
+// should run it in its own goroutine.
+func (m *Manager) RunJanitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.sweep()
+		}
+	}
+}
+
+func (m *Manager) sweep() {
+	cutoff := time.Now().Add(-m.ttl)
+
+	m.mu.Lock()
+	var stale []string
+	for id, u := range m.uploads {
+		if u.Complete() {
+			continue
+		}
+		if u.LastActivity.Before(cutoff) {
+			stale = append(stale, id)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, id := range stale {
+		_ = m.Remove(id)
+	}
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,88 @@
+package uploads
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestAppend_ConcurrentSameOffset(t *testing.T) {
+	m, err := NewManager(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	const chunk = "0123456789"
+	u, err := m.Create(int64(len(chunk)*2), "video.mp4")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]error, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := m.Append(u.ID, 0, strings.NewReader(chunk))
+			results[i] = err
+		}(i)
+	}
+	wg.Wait()
+
+	var ok, mismatch int
+	for _, err := range results {
+		switch err {
+		case nil:
+			ok++
+		case ErrOffsetMismatch:
+			mismatch++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if ok != 1 || mismatch != 1 {
+		t.Fatalf("expected exactly one success and one ErrOffsetMismatch, got ok=%d mismatch=%d", ok, mismatch)
+	}
+
+	status, err := m.Status(u.ID)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.Offset != int64(len(chunk)) {
+		t.Fatalf("Offset = %d, want %d (one write should have been rejected, not both applied)", status.Offset, len(chunk))
+	}
+}
+
+func TestAppend_SequentialWrites(t *testing.T) {
+	m, err := NewManager(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	const part1, part2 = "hello, ", "world"
+	u, err := m.Create(int64(len(part1)+len(part2)), "video.mp4")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if _, err := m.Append(u.ID, 0, strings.NewReader(part1)); err != nil {
+		t.Fatalf("Append part1: %v", err)
+	}
+	status, err := m.Append(u.ID, int64(len(part1)), strings.NewReader(part2))
+	if err != nil {
+		t.Fatalf("Append part2: %v", err)
+	}
+	if !status.Complete() {
+		t.Fatalf("expected upload to be complete after both parts")
+	}
+
+	data, err := os.ReadFile(u.Path)
+	if err != nil {
+		t.Fatalf("reading upload file: %v", err)
+	}
+	if got, want := string(data), part1+part2; got != want {
+		t.Fatalf("file contents = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+package videovalidate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Policy caps how large or long a video of a given codec may be.
+type Policy struct {
+	MaxSizeBytes       int64 `json:"max_size_bytes"`
+	MaxDurationSeconds int64 `json:"max_duration_seconds"`
+}
+
+// Policies maps a video codec name (as reported by ffprobe, e.g. "vp8",
+// "h264") to the limits enforced for it. The zero value of a field means
+// "no limit".
+type Policies map[string]Policy
+
+// DefaultPolicies returns the built-in limits used when no policy config
+// file is supplied.
+func DefaultPolicies() Policies {
+	return Policies{
+		"vp8": {MaxDurationSeconds: 10 * 60},
+		"vp9": {MaxDurationSeconds: 10 * 60},
+	}
+}
+
+// LoadPolicies reads codec policies from a JSON config file. An empty path
+// returns DefaultPolicies.
+func LoadPolicies(path string) (Policies, error) {
+	if path == "" {
+		return DefaultPolicies(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy config: %w", err)
+	}
+
+	var policies Policies
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return nil, fmt.Errorf("failed to parse policy config: %w", err)
+	}
+	return policies, nil
+}
+
+// Enforce checks size and duration against the policy for codec, if one is
+// configured.
+func (p Policies) Enforce(codec string, size int64, duration time.Duration) error {
+	policy, ok := p[codec]
+	if !ok {
+		return nil
+	}
+
+	if policy.MaxSizeBytes > 0 && size > policy.MaxSizeBytes {
+		return fmt.Errorf("%s videos are capped at %d bytes", codec, policy.MaxSizeBytes)
+	}
+	if policy.MaxDurationSeconds > 0 && duration > time.Duration(policy.MaxDurationSeconds)*time.Second {
+		return fmt.Errorf("%s videos are capped at %d seconds", codec, policy.MaxDurationSeconds)
+	}
+	return nil
+}
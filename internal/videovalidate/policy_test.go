@@ -0,0 +1,73 @@
+package videovalidate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolicies_Enforce(t *testing.T) {
+	policies := Policies{
+		"vp8": {MaxDurationSeconds: 60},
+		"h264": {
+			MaxSizeBytes:       1000,
+			MaxDurationSeconds: 120,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		codec    string
+		size     int64
+		duration time.Duration
+		wantErr  bool
+	}{
+		{
+			name:  "codec with no configured policy is unrestricted",
+			codec: "vp9",
+			size:  1 << 40,
+		},
+		{
+			name:     "within both limits",
+			codec:    "h264",
+			size:     500,
+			duration: 60 * time.Second,
+		},
+		{
+			name:     "over the size limit",
+			codec:    "h264",
+			size:     1001,
+			duration: 60 * time.Second,
+			wantErr:  true,
+		},
+		{
+			name:     "over the duration limit",
+			codec:    "h264",
+			size:     500,
+			duration: 121 * time.Second,
+			wantErr:  true,
+		},
+		{
+			name:     "at the duration limit is allowed",
+			codec:    "vp8",
+			duration: 60 * time.Second,
+		},
+		{
+			name:     "one second over a duration-only policy",
+			codec:    "vp8",
+			duration: 61 * time.Second,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policies.Enforce(tt.codec, tt.size, tt.duration)
+			if tt.wantErr && err == nil {
+				t.Fatalf("Enforce(%q, %d, %s) = nil, want an error", tt.codec, tt.size, tt.duration)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("Enforce(%q, %d, %s) = %v, want nil", tt.codec, tt.size, tt.duration, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package videovalidate
+
+import "testing"
+
+func TestFormatExtensions_MatchesDeclaredExtension(t *testing.T) {
+	tests := []struct {
+		name        string
+		container   string
+		declaredExt string
+		wantMatch   bool
+	}{
+		{
+			name:        "mp4 declared for the mov/mp4 alias group",
+			container:   "mov,mp4,m4a,3gp,3g2,mj2",
+			declaredExt: ".mp4",
+			wantMatch:   true,
+		},
+		{
+			name:        "mov declared for the mov/mp4 alias group",
+			container:   "mov,mp4,m4a,3gp,3g2,mj2",
+			declaredExt: ".mov",
+			wantMatch:   true,
+		},
+		{
+			name:        "webm declared for an avi container is a mismatch",
+			container:   "avi",
+			declaredExt: ".webm",
+			wantMatch:   false,
+		},
+		{
+			name:        "avi declared for an mp4 container is a mismatch",
+			container:   "mov,mp4,m4a,3gp,3g2,mj2",
+			declaredExt: ".avi",
+			wantMatch:   false,
+		},
+		{
+			name:        "wmv declared for an asf container",
+			container:   "asf",
+			declaredExt: ".wmv",
+			wantMatch:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, ok := formatExtensions[tt.container]
+			if !ok {
+				t.Fatalf("no formatExtensions entry for container %q", tt.container)
+			}
+			if got := containsExt(allowed, tt.declaredExt); got != tt.wantMatch {
+				t.Fatalf("containsExt(%v, %q) = %v, want %v", allowed, tt.declaredExt, got, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestLooksLikeVideo(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{"video/mp4", true},
+		{"video/webm", true},
+		{"application/octet-stream", true},
+		{"text/plain; charset=utf-8", false},
+		{"image/png", false},
+	}
+
+	for _, tt := range tests {
+		if got := looksLikeVideo(tt.contentType); got != tt.want {
+			t.Fatalf("looksLikeVideo(%q) = %v, want %v", tt.contentType, got, tt.want)
+		}
+	}
+}